@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// traceLocation holds the -log_backtrace_at trigger set: file:line pairs
+// at which a full goroutine stack trace is appended to the log line,
+// ported from glog's -log_backtrace_at.
+type traceLocation struct {
+	mu    sync.Mutex
+	sites map[string]map[int]struct{} // basename -> set of trigger lines
+	empty int32                       // atomic: 1 when sites is empty, for a cheap fast-path skip
+}
+
+var backtraceAt traceLocation
+
+func init() {
+	atomic.StoreInt32(&backtraceAt.empty, 1)
+	flag.Var(&backtraceAt, "log_backtrace_at", "when logging hits line file:N, emit a stack trace")
+}
+
+// String is part of the flag.Value interface.
+func (t *traceLocation) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var b strings.Builder
+	for file, lines := range t.sites {
+		for line := range lines {
+			if b.Len() > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(&b, "%s:%d", file, line)
+		}
+	}
+	return b.String()
+}
+
+// Set is part of the flag.Value interface and also backs SetBacktraceAt. It
+// parses a comma-separated list of file:line triggers, e.g.
+// "server.go:123,handler.go:45".
+func (t *traceLocation) Set(value string) error {
+	sites := make(map[string]map[int]struct{})
+	if value != "" {
+		for _, site := range strings.Split(value, ",") {
+			fl := strings.Split(site, ":")
+			if len(fl) != 2 {
+				return fmt.Errorf("syntax error: expect file:line, got %q", site)
+			}
+			line, err := strconv.Atoi(fl[1])
+			if err != nil {
+				return fmt.Errorf("syntax error: expect file:line, got %q", site)
+			}
+			if sites[fl[0]] == nil {
+				sites[fl[0]] = make(map[int]struct{})
+			}
+			sites[fl[0]][line] = struct{}{}
+		}
+	}
+
+	t.mu.Lock()
+	t.sites = sites
+	t.mu.Unlock()
+
+	if len(sites) == 0 {
+		atomic.StoreInt32(&t.empty, 1)
+	} else {
+		atomic.StoreInt32(&t.empty, 0)
+	}
+	return nil
+}
+
+// SetBacktraceAt configures -log_backtrace_at-style triggers programmatically.
+func SetBacktraceAt(spec string) error {
+	return backtraceAt.Set(spec)
+}
+
+// matches reports whether basename file at line is a configured backtrace
+// trigger.
+func (t *traceLocation) matches(file string, line int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	lines, ok := t.sites[file]
+	if !ok {
+		return false
+	}
+	_, ok = lines[line]
+	return ok
+}
+
+const maxStackSize = 64 * 1024
+
+// captureStack returns a bounded, growable dump of all goroutine stacks.
+func captureStack() []byte {
+	buf := make([]byte, 4096)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxStackSize {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// checkBacktraceAt is called from Logger.emit with the calldepth of the
+// original log call. When the caller's file:line is a configured trigger it
+// appends a stack trace to txt and arranges for the parsed frames to be
+// attached to the Sentry event as a Stacktrace. The appended text is part of
+// txt, so it also reaches Sentry inside the event's message. It is skipped
+// cheaply when no triggers are configured.
+func checkBacktraceAt(calldepth int, txt string) (string, func()) {
+	if atomic.LoadInt32(&backtraceAt.empty) == 1 {
+		return txt, func() {}
+	}
+
+	_, file, line, ok := runtime.Caller(calldepth)
+	if !ok || !backtraceAt.matches(filepath.Base(file), line) {
+		return txt, func() {}
+	}
+
+	txt += "\n" + string(captureStack())
+	setSentryStacktrace(sentry.NewStacktrace())
+	return txt, clearSentryStacktrace
+}
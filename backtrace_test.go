@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestCheckBacktraceAtMatchesCallerSite is a regression test for the
+// calldepth off-by-one that made -log_backtrace_at compare against this
+// package's own internal call site instead of the caller's.
+func TestCheckBacktraceAtMatchesCallerSite(t *testing.T) {
+	defer backtraceAt.Set("")
+
+	var buf bytes.Buffer
+	l := Init("backtrace_test", false, false, &buf)
+
+	if err := SetBacktraceAt("backtrace_test.go:21"); err != nil {
+		t.Fatalf("SetBacktraceAt: %v", err)
+	}
+	l.Info("triggered") // must stay on line 21; SetBacktraceAt above points at it.
+
+	if !strings.Contains(buf.String(), "goroutine") {
+		t.Fatalf("expected a stack trace to be appended for the configured trigger site, got: %q", buf.String())
+	}
+}
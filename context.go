@@ -0,0 +1,154 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+type loggerContextKey struct{}
+
+// NewContext returns a new context carrying l, retrievable later with
+// FromContext or used implicitly by the *Context logging functions.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, &Entry{logger: l, Fields: Fields{}})
+}
+
+// FromContext returns the Logger attached to ctx via NewContext/WithValues,
+// or the default logger if none was attached.
+func FromContext(ctx context.Context) *Logger {
+	return entryFromContext(ctx).logger
+}
+
+// entryFromContext returns the contextual Entry attached to ctx, falling
+// back to the default logger with no fields if none was attached.
+func entryFromContext(ctx context.Context) *Entry {
+	if e, ok := ctx.Value(loggerContextKey{}).(*Entry); ok && e != nil {
+		return e
+	}
+	return &Entry{logger: defaultLogger, Fields: Fields{}}
+}
+
+// WithValues returns a new context carrying a logger enriched with the
+// given alternating key/value pairs (e.g. "traceID", id, "requestID", rid),
+// merged with any fields already attached to ctx, so handler chains can
+// accumulate fields without threading a *Logger explicitly.
+func WithValues(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	e := entryFromContext(ctx)
+	fields := make(Fields, len(e.Fields)+len(keysAndValues)/2)
+	for k, v := range e.Fields {
+		fields[k] = v
+	}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		fields[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+	}
+	return context.WithValue(ctx, loggerContextKey{}, &Entry{logger: e.logger, Fields: fields})
+}
+
+// InfoContext logs with the Info severity using the logger and fields
+// attached to ctx.
+func InfoContext(ctx context.Context, v ...interface{}) {
+	entryFromContext(ctx).log(sInfo, 0, tagInfo, fmt.Sprint(v...))
+}
+
+// InfoContextln logs with the Info severity using the logger and fields
+// attached to ctx.
+func InfoContextln(ctx context.Context, v ...interface{}) {
+	entryFromContext(ctx).log(sInfo, 0, tagInfo, fmt.Sprintln(v...))
+}
+
+// InfoContextf logs with the Info severity using the logger and fields
+// attached to ctx.
+func InfoContextf(ctx context.Context, format string, v ...interface{}) {
+	entryFromContext(ctx).log(sInfo, 0, tagInfo, fmt.Sprintf(format, v...))
+}
+
+// InfoContextDepth acts as InfoContext but uses depth to determine which
+// call frame to log.
+func InfoContextDepth(ctx context.Context, depth int, v ...interface{}) {
+	entryFromContext(ctx).log(sInfo, depth, tagInfo, fmt.Sprint(v...))
+}
+
+// WarningContext logs with the Warning severity using the logger and
+// fields attached to ctx.
+func WarningContext(ctx context.Context, v ...interface{}) {
+	entryFromContext(ctx).log(sWarning, 0, tagWarning, fmt.Sprint(v...))
+}
+
+// WarningContextln logs with the Warning severity using the logger and
+// fields attached to ctx.
+func WarningContextln(ctx context.Context, v ...interface{}) {
+	entryFromContext(ctx).log(sWarning, 0, tagWarning, fmt.Sprintln(v...))
+}
+
+// WarningContextf logs with the Warning severity using the logger and
+// fields attached to ctx.
+func WarningContextf(ctx context.Context, format string, v ...interface{}) {
+	entryFromContext(ctx).log(sWarning, 0, tagWarning, fmt.Sprintf(format, v...))
+}
+
+// WarningContextDepth acts as WarningContext but uses depth to determine
+// which call frame to log.
+func WarningContextDepth(ctx context.Context, depth int, v ...interface{}) {
+	entryFromContext(ctx).log(sWarning, depth, tagWarning, fmt.Sprint(v...))
+}
+
+// ErrorContext logs with the Error severity using the logger and fields
+// attached to ctx.
+func ErrorContext(ctx context.Context, v ...interface{}) {
+	entryFromContext(ctx).log(sError, 0, tagError, fmt.Sprint(v...))
+}
+
+// ErrorContextln logs with the Error severity using the logger and fields
+// attached to ctx.
+func ErrorContextln(ctx context.Context, v ...interface{}) {
+	entryFromContext(ctx).log(sError, 0, tagError, fmt.Sprintln(v...))
+}
+
+// ErrorContextf logs with the Error severity using the logger and fields
+// attached to ctx.
+func ErrorContextf(ctx context.Context, format string, v ...interface{}) {
+	entryFromContext(ctx).log(sError, 0, tagError, fmt.Sprintf(format, v...))
+}
+
+// ErrorContextDepth acts as ErrorContext but uses depth to determine which
+// call frame to log.
+func ErrorContextDepth(ctx context.Context, depth int, v ...interface{}) {
+	entryFromContext(ctx).log(sError, depth, tagError, fmt.Sprint(v...))
+}
+
+// FatalContext logs with the Fatal severity using the logger and fields
+// attached to ctx, then ends with os.Exit(1).
+func FatalContext(ctx context.Context, v ...interface{}) {
+	e := entryFromContext(ctx)
+	e.log(sFatal, 0, tagFatal, fmt.Sprint(v...))
+	e.logger.Close()
+	os.Exit(1)
+}
+
+// FatalContextln logs with the Fatal severity using the logger and fields
+// attached to ctx, then ends with os.Exit(1).
+func FatalContextln(ctx context.Context, v ...interface{}) {
+	e := entryFromContext(ctx)
+	e.log(sFatal, 0, tagFatal, fmt.Sprintln(v...))
+	e.logger.Close()
+	os.Exit(1)
+}
+
+// FatalContextf logs with the Fatal severity using the logger and fields
+// attached to ctx, then ends with os.Exit(1).
+func FatalContextf(ctx context.Context, format string, v ...interface{}) {
+	e := entryFromContext(ctx)
+	e.log(sFatal, 0, tagFatal, fmt.Sprintf(format, v...))
+	e.logger.Close()
+	os.Exit(1)
+}
+
+// FatalContextDepth acts as FatalContext but uses depth to determine which
+// call frame to log, then ends with os.Exit(1).
+func FatalContextDepth(ctx context.Context, depth int, v ...interface{}) {
+	e := entryFromContext(ctx)
+	e.log(sFatal, depth, tagFatal, fmt.Sprint(v...))
+	e.logger.Close()
+	os.Exit(1)
+}
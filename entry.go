@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+)
+
+// Entry carries key/value context accumulated via WithField/WithFields
+// through to Info/Warning/Error/Fatal calls, borrowing the logrus-style
+// Entry model.
+type Entry struct {
+	logger *Logger
+	Fields Fields
+}
+
+// WithFields returns an Entry that carries fields alongside future log calls.
+func (l *Logger) WithFields(fields Fields) *Entry {
+	merged := make(Fields, len(fields))
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Entry{logger: l, Fields: merged}
+}
+
+// WithField returns an Entry carrying a single key/value pair.
+func (l *Logger) WithField(key string, value interface{}) *Entry {
+	return l.WithFields(Fields{key: value})
+}
+
+// WithFields uses the default logger to return an Entry carrying fields.
+func WithFields(fields Fields) *Entry {
+	return defaultLogger.WithFields(fields)
+}
+
+// WithField uses the default logger to return an Entry carrying a single
+// key/value pair.
+func WithField(key string, value interface{}) *Entry {
+	return defaultLogger.WithField(key, value)
+}
+
+// WithFields returns a new Entry merging additional fields into e's
+// existing ones, so handler chains can accumulate context.
+func (e *Entry) WithFields(fields Fields) *Entry {
+	merged := make(Fields, len(e.Fields)+len(fields))
+	for k, v := range e.Fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Entry{logger: e.logger, Fields: merged}
+}
+
+// WithField returns a new Entry with an additional key/value pair.
+func (e *Entry) WithField(key string, value interface{}) *Entry {
+	return e.WithFields(Fields{key: value})
+}
+
+func (e *Entry) log(s severity, depth int, tag, msg string) {
+	f := e.logger.formatter
+	if f == nil {
+		f = TextFormatter{}
+	}
+	rendered, err := f.Format(tag, msg, e.Fields)
+	if err != nil {
+		rendered = msg
+	}
+	// +3 skips Entry.log, withSentryFields and the closure below.
+	withSentryFields(e.Fields, func() {
+		e.logger.outputFormatted(s, depth+3, rendered)
+	})
+}
+
+// Info logs with the Info severity, including the Entry's fields.
+func (e *Entry) Info(v ...interface{}) {
+	e.log(sInfo, 0, tagInfo, fmt.Sprint(v...))
+}
+
+// Infoln logs with the Info severity, including the Entry's fields.
+func (e *Entry) Infoln(v ...interface{}) {
+	e.log(sInfo, 0, tagInfo, fmt.Sprintln(v...))
+}
+
+// Infof logs with the Info severity, including the Entry's fields.
+func (e *Entry) Infof(format string, v ...interface{}) {
+	e.log(sInfo, 0, tagInfo, fmt.Sprintf(format, v...))
+}
+
+// Warning logs with the Warning severity, including the Entry's fields.
+func (e *Entry) Warning(v ...interface{}) {
+	e.log(sWarning, 0, tagWarning, fmt.Sprint(v...))
+}
+
+// Warningln logs with the Warning severity, including the Entry's fields.
+func (e *Entry) Warningln(v ...interface{}) {
+	e.log(sWarning, 0, tagWarning, fmt.Sprintln(v...))
+}
+
+// Warningf logs with the Warning severity, including the Entry's fields.
+func (e *Entry) Warningf(format string, v ...interface{}) {
+	e.log(sWarning, 0, tagWarning, fmt.Sprintf(format, v...))
+}
+
+// Error logs with the Error severity, including the Entry's fields.
+func (e *Entry) Error(v ...interface{}) {
+	e.log(sError, 0, tagError, fmt.Sprint(v...))
+}
+
+// Errorln logs with the Error severity, including the Entry's fields.
+func (e *Entry) Errorln(v ...interface{}) {
+	e.log(sError, 0, tagError, fmt.Sprintln(v...))
+}
+
+// Errorf logs with the Error severity, including the Entry's fields.
+func (e *Entry) Errorf(format string, v ...interface{}) {
+	e.log(sError, 0, tagError, fmt.Sprintf(format, v...))
+}
+
+// Fatal logs with the Fatal severity, including the Entry's fields, then
+// ends with os.Exit(1).
+func (e *Entry) Fatal(v ...interface{}) {
+	e.log(sFatal, 0, tagFatal, fmt.Sprint(v...))
+	e.logger.Close()
+	os.Exit(1)
+}
+
+// Fatalln logs with the Fatal severity, including the Entry's fields, then
+// ends with os.Exit(1).
+func (e *Entry) Fatalln(v ...interface{}) {
+	e.log(sFatal, 0, tagFatal, fmt.Sprintln(v...))
+	e.logger.Close()
+	os.Exit(1)
+}
+
+// Fatalf logs with the Fatal severity, including the Entry's fields, then
+// ends with os.Exit(1).
+func (e *Entry) Fatalf(format string, v ...interface{}) {
+	e.log(sFatal, 0, tagFatal, fmt.Sprintf(format, v...))
+	e.logger.Close()
+	os.Exit(1)
+}
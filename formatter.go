@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Fields is a map of structured context attached to a log entry via
+// WithField/WithFields.
+type Fields map[string]interface{}
+
+// Formatter renders a severity tag, message and fields into the string
+// written to the log file and stderr/stdout. Built-in implementations are
+// TextFormatter, JSONFormatter and LogfmtFormatter.
+type Formatter interface {
+	Format(tag, msg string, fields Fields) (string, error)
+}
+
+// TextFormatter renders "message key=value key=value"; the severity tag is
+// not part of its output. It is the default formatter for structured
+// entries, whose lines then still get the log.Logger date/file/tag prefix;
+// once set via SetFormatter, its lines carry no prefix or tag at all.
+type TextFormatter struct{}
+
+// Format implements Formatter.
+func (TextFormatter) Format(tag, msg string, fields Fields) (string, error) {
+	var b bytes.Buffer
+	b.WriteString(msg)
+	for _, k := range sortedKeys(fields) {
+		fmt.Fprintf(&b, " %s=%v", k, fields[k])
+	}
+	return b.String(), nil
+}
+
+// JSONFormatter renders each entry as a single JSON object.
+type JSONFormatter struct{}
+
+// Format implements Formatter.
+func (JSONFormatter) Format(tag, msg string, fields Fields) (string, error) {
+	data := make(map[string]interface{}, len(fields)+2)
+	for k, v := range fields {
+		data[k] = v
+	}
+	data["level"] = tag
+	data["msg"] = msg
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// LogfmtFormatter renders entries as space-separated key=value pairs in
+// logfmt style, e.g. level=INFO msg="listening on" addr=:8080.
+type LogfmtFormatter struct{}
+
+// Format implements Formatter.
+func (LogfmtFormatter) Format(tag, msg string, fields Fields) (string, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "level=%s msg=%s", strings.TrimRight(tag, " :"), logfmtValue(msg))
+	for _, k := range sortedKeys(fields) {
+		fmt.Fprintf(&b, " %s=%s", k, logfmtValue(fields[k]))
+	}
+	return b.String(), nil
+}
+
+func logfmtValue(v interface{}) string {
+	s := fmt.Sprint(v)
+	if s == "" || bytes.ContainsAny([]byte(s), " =\"") {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
+
+func sortedKeys(fields Fields) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SetFormatter sets the formatter used to render log entries, both
+// structured ones created via WithFields/WithField and plain
+// Info/Warning/Error/Fatal calls. Once set, it owns the entire output line
+// for JSON/logfmt formatters to stay parseable, rather than being appended
+// after the standard log.Logger date/file/tag prefix. With no formatter
+// set, calls keep writing via that prefix, preserving the package's
+// historical output by default.
+func (l *Logger) SetFormatter(f Formatter) {
+	l.formatter = f
+}
+
+// SetFormatter sets the formatter used by the default logger.
+func SetFormatter(f Formatter) {
+	defaultLogger.SetFormatter(f)
+}
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestJSONFormatterDoesNotDoubleEncode is a regression test for
+// Logger.output re-running Entry-rendered text through the formatter a
+// second time, which turned a flat JSON record into one whose "msg" held
+// an escaped, nested JSON string instead of the real fields.
+func TestJSONFormatterDoesNotDoubleEncode(t *testing.T) {
+	var buf bytes.Buffer
+	l := Init("formatter_test_json", false, false, &buf)
+	l.SetFormatter(JSONFormatter{})
+
+	l.WithField("user", "alice").Info("hi")
+
+	var rec map[string]interface{}
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &rec); err != nil {
+		t.Fatalf("output is not a single flat JSON object: %v (got %q)", err, buf.String())
+	}
+	if rec["user"] != "alice" {
+		t.Errorf("expected top-level field user=alice, got %v", rec)
+	}
+	if msg, _ := rec["msg"].(string); msg != "hi" || strings.HasPrefix(msg, "{") {
+		t.Errorf("expected msg=\"hi\", got %v", rec["msg"])
+	}
+}
+
+// TestFormatterBypassesLogPrefix is a regression test for formatted lines
+// still being appended after the underlying log.Logger's date/file/tag
+// prefix, which made JSON/logfmt output unparseable as shipped.
+func TestFormatterBypassesLogPrefix(t *testing.T) {
+	var buf bytes.Buffer
+	l := Init("formatter_test_prefix", false, false, &buf)
+	l.SetFormatter(JSONFormatter{})
+
+	l.Info("hi")
+
+	line := strings.TrimSpace(buf.String())
+	var rec map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &rec); err != nil {
+		t.Fatalf("expected a bare parseable JSON line with no log.Logger prefix, got %q: %v", line, err)
+	}
+}
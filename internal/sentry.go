@@ -1,24 +1,81 @@
 package internal
 
 import (
-	"github.com/getsentry/sentry-go"
 	"sync"
 	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+const flashTimeout = 3 * time.Second
+const bufferSize = 64
+
+var (
+	mu     sync.Mutex
+	events chan event
+	wg     sync.WaitGroup
 )
 
-const flashTimeout = 3*time.Second
+type event struct {
+	level sentry.Level
+	text  string
+}
 
-var connected = false
-var ErrorLog = &snio{ sentry.LevelError }
-var WarnLog = &snio{ sentry.LevelWarning }
-var wg sync.WaitGroup
+var ErrorLog = &snio{sentry.LevelError}
+var WarnLog = &snio{sentry.LevelWarning}
 
+// ConnectSentry initializes the Sentry client and starts the background
+// worker that drains queued events, rather than spawning a goroutine per
+// log line.
 func ConnectSentry(dsn string) error {
 	if err := sentry.Init(sentry.ClientOptions{Dsn: dsn}); err != nil {
 		return err
-	} else {
-		connected = true
-		return nil
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if events == nil {
+		events = make(chan event, bufferSize)
+		go worker()
+	}
+	return nil
+}
+
+func worker() {
+	for e := range events {
+		sentry.WithScope(func(scope *sentry.Scope) {
+			scope.SetLevel(e.level)
+			sentry.CaptureMessage(e.text)
+		})
+		sentry.Flush(flashTimeout)
+		wg.Done()
+	}
+}
+
+func connected() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return events != nil
+}
+
+// push enqueues e, dropping the oldest queued event to make room rather
+// than blocking the caller when the buffer is full.
+func push(e event) {
+	wg.Add(1)
+	select {
+	case events <- e:
+		return
+	default:
+	}
+	select {
+	case <-events:
+		wg.Done()
+	default:
+	}
+	select {
+	case events <- e:
+	default:
+		wg.Done()
 	}
 }
 
@@ -27,35 +84,20 @@ type snio struct {
 }
 
 func (sn *snio) Write(p []byte) (n int, err error) {
-	if connected {
-		wg.Add(1)
-		go func() {
-			sentry.WithScope(func(scope *sentry.Scope) {
-				scope.SetLevel(sn.level);
-				sentry.CaptureMessage(string(p))
-			})
-			sentry.Flush(flashTimeout)
-			wg.Done()
-		}()
+	if connected() {
+		push(event{sn.level, string(p)})
 	}
-	return 0,nil
+	return 0, nil
 }
 
 func Info(text string) {
-	if connected {
-		wg.Add(1)
-		go func() {
-			sentry.WithScope(func(scope *sentry.Scope) {
-				scope.SetLevel(sentry.LevelInfo);
-				sentry.CaptureMessage(text)
-			})
-			sentry.Flush(flashTimeout)
-			wg.Done()
-		}()
+	if connected() {
+		push(event{sentry.LevelInfo, text})
 		Wait()
 	}
 }
 
+// Wait blocks until all queued events have been sent.
 func Wait() {
 	wg.Wait()
 }
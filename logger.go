@@ -23,7 +23,6 @@ import (
 	"log"
 	"os"
 	"sync"
-	"time"
 )
 
 type severity int
@@ -49,6 +48,23 @@ const (
 	initText = "ERROR: Logging before logger.Init.\n"
 )
 
+// severityTag returns the tag Format is given for plain (non-Entry) log
+// calls, matching the tags Entry.log passes for structured ones.
+func severityTag(s severity) string {
+	switch s {
+	case sInfo:
+		return tagInfo
+	case sWarning:
+		return tagWarning
+	case sError:
+		return tagError
+	case sFatal:
+		return tagFatal
+	default:
+		return ""
+	}
+}
+
 var (
 	logLock       sync.Mutex
 	defaultLogger *Logger
@@ -112,7 +128,6 @@ func Init(name string, verbose, _ bool, logFile io.Writer) *Logger {
 		fatalLog:   makeLog(sFatal, sentryFatalLog),
 	}
 
-	l.closers = append(l.closers, sentryFatalLog)
 	if logFile != nil {
 		if c, ok := logFile.(io.Closer); ok && c != nil {
 			l.closers = append(l.closers, c)
@@ -130,9 +145,13 @@ func Init(name string, verbose, _ bool, logFile io.Writer) *Logger {
 	return &l
 }
 
-// Close closes the default logger.
+// Close closes the default logger and, if one is connected, shuts down the
+// shared Sentry worker, draining any events still queued. Use this (or
+// CloseSentry directly) for process shutdown rather than relying on a
+// single *Logger.Close, since every Logger shares the one Sentry worker.
 func Close() {
 	defaultLogger.Close()
+	CloseSentry()
 }
 
 // A Logger represents an active logging object. Multiple loggers can be used
@@ -144,20 +163,71 @@ type Logger struct {
 	fatalLog    *log.Logger
 	closers     []io.Closer
 	initialized bool
+	formatter   Formatter
 }
 
+// output logs txt, running it through l.formatter (if any) first. Used by
+// the plain Info/Warning/Error/Fatal family, whose txt has not already been
+// rendered by a formatter.
 func (l *Logger) output(s severity, depth int, txt string) {
+	if l.formatter != nil {
+		if rendered, err := l.formatter.Format(severityTag(s), txt, nil); err == nil {
+			txt = rendered
+		}
+	}
+	l.emit(s, 4+depth, txt)
+}
+
+// outputFormatted logs txt as-is. Used by Entry.log, whose txt has already
+// been rendered through a formatter, so it must not be formatted again.
+func (l *Logger) outputFormatted(s severity, depth int, txt string) {
+	l.emit(s, 4+depth, txt)
+}
+
+// emit appends a backtrace if txt's call site is a configured trigger, then
+// writes txt at calldepth. With no formatter set it goes through the
+// underlying log.Logger, picking up its usual date/file/tag prefix; with a
+// formatter set, that prefix is bypassed so the formatter owns the whole
+// line (required for JSONFormatter/LogfmtFormatter to emit a parseable
+// line).
+func (l *Logger) emit(s severity, calldepth int, txt string) {
+	txt, cleanup := checkBacktraceAt(calldepth, txt)
+	defer cleanup()
+
 	logLock.Lock()
 	defer logLock.Unlock()
+
+	if l.formatter != nil {
+		fmt.Fprintln(l.writerFor(s).Writer(), txt)
+		return
+	}
+
 	switch s {
 	case sInfo:
-		l.infoLog.Output(3+depth, txt)
+		l.infoLog.Output(calldepth, txt)
 	case sWarning:
-		l.warningLog.Output(3+depth, txt)
+		l.warningLog.Output(calldepth, txt)
 	case sError:
-		l.errorLog.Output(3+depth, txt)
+		l.errorLog.Output(calldepth, txt)
 	case sFatal:
-		l.fatalLog.Output(3+depth, txt)
+		l.fatalLog.Output(calldepth, txt)
+	default:
+		panic(fmt.Sprintln("unrecognized severity:", s))
+	}
+}
+
+// writerFor returns the *log.Logger backing severity s, so emit can reach
+// its underlying io.Writer directly when bypassing its prefix.
+func (l *Logger) writerFor(s severity) *log.Logger {
+	switch s {
+	case sInfo:
+		return l.infoLog
+	case sWarning:
+		return l.warningLog
+	case sError:
+		return l.errorLog
+	case sFatal:
+		return l.fatalLog
 	default:
 		panic(fmt.Sprintln("unrecognized severity:", s))
 	}
@@ -400,35 +470,7 @@ func Fatalf(format string, v ...interface{}) {
 	os.Exit(1)
 }
 
-const flashTimeout = 3 * time.Second
-
 var sentryFatalLog = &snio{sentry.LevelFatal}
 var sentryErrorLog = &snio{sentry.LevelError}
 var sentryWarnLog = &snio{sentry.LevelWarning}
 var sentryInfoLog = &snio{sentry.LevelInfo}
-
-type snio struct {
-	level sentry.Level
-}
-
-func sentryOutput(p []byte, level sentry.Level) {
-	sentry.WithScope(func(scope *sentry.Scope) {
-		scope.SetLevel(level)
-		sentry.CaptureMessage(string(p))
-	})
-	if level == sentry.LevelFatal {
-		sentry.Flush(flashTimeout)
-	}
-}
-
-func (sn *snio) Write(p []byte) (n int, err error) {
-	if sentry.CurrentHub().Client() != nil {
-		sentryOutput(p, sn.level)
-	}
-	return 0, nil
-}
-
-func (sn *snio) Close() error {
-	sentry.Flush(flashTimeout)
-	return nil
-}
@@ -16,6 +16,3 @@ func Rinfof(a string, b ...interface{}) {
 	internal.Info(a)
 }
 
-func ConnectSentry(dsn string) error {
-	return internal.ConnectSentry(dsn)
-}
@@ -1,21 +1,73 @@
 package remote
 
 import (
-	"github.com/getsentry/sentry-go"
 	"sync"
+
+	"github.com/getsentry/sentry-go"
+)
+
+const bufferSize = 64
+
+var (
+	mu     sync.Mutex
+	events chan event
 )
 
-var connected = false
-var ErrorLog = &snio{ isErrLog: true }
-var WarnLog = &snio{ false }
-var wg sync.WaitGroup
+type event struct {
+	level sentry.Level
+	text  string
+}
+
+var ErrorLog = &snio{isErrLog: true}
+var WarnLog = &snio{false}
 
+// ConnectSentry initializes the Sentry client and starts the background
+// worker that drains queued events, rather than spawning a goroutine per
+// log line.
 func ConnectSentry(dsn string) error {
 	if err := sentry.Init(sentry.ClientOptions{Dsn: dsn}); err != nil {
 		return err
-	} else {
-		connected = true
-		return nil
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if events == nil {
+		events = make(chan event, bufferSize)
+		go worker()
+	}
+	return nil
+}
+
+func worker() {
+	for e := range events {
+		sentry.WithScope(func(scope *sentry.Scope) {
+			scope.SetLevel(e.level)
+			sentry.CaptureMessage(e.text)
+		})
+	}
+}
+
+func connected() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return events != nil
+}
+
+// push enqueues e, dropping the oldest queued event to make room rather
+// than blocking the caller when the buffer is full.
+func push(e event) {
+	select {
+	case events <- e:
+		return
+	default:
+	}
+	select {
+	case <-events:
+	default:
+	}
+	select {
+	case events <- e:
+	default:
 	}
 }
 
@@ -24,19 +76,12 @@ type snio struct {
 }
 
 func (sn *snio) Write(p []byte) (n int, err error) {
-	if connected {
-		wg.Add(1)
-		go func() {
-			sentry.WithScope(func(scope *sentry.Scope) {
-				if (sn.isErrLog) {
-					scope.SetLevel(sentry.LevelError);
-				} else {
-					scope.SetLevel(sentry.LevelWarning);
-				}
-				sentry.CaptureMessage(string(p))
-			})
-			wg.Done()
-		}()
+	if connected() {
+		level := sentry.LevelWarning
+		if sn.isErrLog {
+			level = sentry.LevelError
+		}
+		push(event{level, string(p)})
 	}
-	return 0,nil
+	return 0, nil
 }
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRotateNoDataLossOnCollision is a regression test for rotated file
+// names clobbering each other when multiple size-triggered rotations land
+// within the same second.
+func TestRotateNoDataLossOnCollision(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w, err := NewRotatingFile(path, RotateOptions{MaxSize: 1})
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	defer w.Close()
+
+	const n = 10
+	for i := 0; i < n; i++ {
+		if _, err := w.Write([]byte(fmt.Sprintf("line-%d\n", i))); err != nil {
+			t.Fatalf("Write %d: %v", i, err)
+		}
+	}
+
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, m := range matches {
+		if m == path+".current" || m == path+".current.tmp" {
+			continue
+		}
+		b, err := os.ReadFile(m)
+		if err != nil {
+			t.Fatalf("ReadFile %s: %v", m, err)
+		}
+		seen[string(b)] = true
+	}
+
+	for i := 0; i < n-1; i++ {
+		want := fmt.Sprintf("line-%d\n", i)
+		if !seen[want] {
+			t.Errorf("rotated segment for %q was lost, survivors: %v", want, seen)
+		}
+	}
+}
+
+// TestPruneExcludesCurrentSymlink is a regression test for pruneLocked
+// counting the path+".current" symlink as a backup, which always starves
+// out a real rotated segment.
+func TestPruneExcludesCurrentSymlink(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w, err := NewRotatingFile(path, RotateOptions{MaxSize: 1, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	defer w.Close()
+
+	for i := 0; i < 4; i++ {
+		if _, err := w.Write([]byte(fmt.Sprintf("line-%d\n", i))); err != nil {
+			t.Fatalf("Write %d: %v", i, err)
+		}
+	}
+
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	var backups int
+	for _, m := range matches {
+		if m == path+".current" || m == path+".current.tmp" {
+			continue
+		}
+		backups++
+	}
+	if backups != 2 {
+		t.Errorf("expected MaxBackups=2 real rotated segments to survive, got %d (matches: %v)", backups, matches)
+	}
+}
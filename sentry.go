@@ -0,0 +1,383 @@
+package logger
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sudachen/logger/internal"
+)
+
+const flashTimeout = 3 * time.Second
+
+// breadcrumbLimit bounds how many recent lower-severity (below Error) log
+// lines are kept to attach as breadcrumbs on every event sent after them.
+const breadcrumbLimit = 20
+
+// SentryOptions configures the background worker started by ConnectSentry.
+type SentryOptions struct {
+	// BufferSize is the capacity of the event channel. Defaults to 256.
+	BufferSize int
+	// FlushInterval is how often batched events are sent even if BatchSize
+	// hasn't been reached. Defaults to 3s.
+	FlushInterval time.Duration
+	// BatchSize is how many events are coalesced before being sent early.
+	// Defaults to 20.
+	BatchSize int
+	// DropOldest makes a full buffer drop its oldest queued event to make
+	// room for a new one instead of blocking the caller.
+	DropOldest bool
+	// SampleRate optionally thins events per severity, e.g.
+	// {sentry.LevelInfo: 0.1} sends roughly 1 in 10 info events. Severities
+	// absent from the map are always sent.
+	SampleRate map[sentry.Level]float64
+}
+
+type sentryEvent struct {
+	level      sentry.Level
+	message    string
+	fields     Fields
+	time       time.Time
+	flushed    chan struct{}
+	stacktrace *sentry.Stacktrace
+}
+
+// sentryWorker batches and sends log events to Sentry off the caller's
+// goroutine, replacing the previous inline sentry.CaptureMessage call.
+type sentryWorker struct {
+	opts   SentryOptions
+	events chan sentryEvent
+	wg     sync.WaitGroup
+
+	// closeMu guards closed and serializes it against push/Flush sending on
+	// events, so shutdown can never close the channel out from under an
+	// in-flight send: push/Flush hold the read side while they send,
+	// shutdown takes the write side to flip closed and close the channel.
+	closeMu sync.RWMutex
+	closed  bool
+
+	crumbMu     sync.Mutex
+	breadcrumbs []sentryEvent
+}
+
+var (
+	sentryMu sync.Mutex
+	sentryW  *sentryWorker
+)
+
+// ConnectSentry initializes the Sentry client and starts a single background
+// worker that drains a bounded channel of events, coalescing them into
+// batches instead of spawning a goroutine per log line.
+func ConnectSentry(dsn string, opts SentryOptions) error {
+	if err := internal.ConnectSentry(dsn); err != nil {
+		return err
+	}
+
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 256
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 20
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = flashTimeout
+	}
+
+	w := &sentryWorker{
+		opts:   opts,
+		events: make(chan sentryEvent, opts.BufferSize),
+	}
+	w.wg.Add(1)
+	go w.run()
+
+	sentryMu.Lock()
+	sentryW = w
+	sentryMu.Unlock()
+	return nil
+}
+
+func currentSentryWorker() *sentryWorker {
+	sentryMu.Lock()
+	defer sentryMu.Unlock()
+	return sentryW
+}
+
+func (w *sentryWorker) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]sentryEvent, 0, w.opts.BatchSize)
+	flush := func() {
+		for _, e := range batch {
+			w.send(e)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case e, ok := <-w.events:
+			if !ok {
+				flush()
+				return
+			}
+			if e.flushed != nil {
+				flush()
+				sentry.Flush(flashTimeout)
+				close(e.flushed)
+				continue
+			}
+			batch = append(batch, e)
+			if len(batch) >= w.opts.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (w *sentryWorker) send(e sentryEvent) {
+	if !w.sampled(e.level) {
+		return
+	}
+
+	ev := sentry.NewEvent()
+	ev.Level = e.level
+	ev.Message = e.message
+	ev.Timestamp = e.time
+	for k, v := range e.fields {
+		if s, ok := v.(string); ok {
+			ev.Tags[k] = s
+		} else {
+			ev.Extra[k] = v
+		}
+	}
+
+	w.crumbMu.Lock()
+	for _, b := range w.breadcrumbs {
+		ev.Breadcrumbs = append(ev.Breadcrumbs, &sentry.Breadcrumb{
+			Level:     b.level,
+			Message:   b.message,
+			Timestamp: b.time,
+		})
+	}
+	if e.level != sentry.LevelError && e.level != sentry.LevelFatal {
+		w.breadcrumbs = append(w.breadcrumbs, e)
+		if len(w.breadcrumbs) > breadcrumbLimit {
+			w.breadcrumbs = w.breadcrumbs[len(w.breadcrumbs)-breadcrumbLimit:]
+		}
+	}
+	w.crumbMu.Unlock()
+
+	if e.stacktrace != nil {
+		ev.Threads = []sentry.Thread{{Stacktrace: e.stacktrace, Current: true}}
+	}
+
+	sentry.CaptureEvent(ev)
+}
+
+func (w *sentryWorker) sampled(level sentry.Level) bool {
+	rate, ok := w.opts.SampleRate[level]
+	if !ok || rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+	return rand.Float64() < rate
+}
+
+// push enqueues e according to the worker's backpressure policy: block
+// until there is room, or drop the oldest queued event to make room. It is
+// a no-op once the worker has started shutting down.
+func (w *sentryWorker) push(e sentryEvent) {
+	w.closeMu.RLock()
+	defer w.closeMu.RUnlock()
+	if w.closed {
+		return
+	}
+
+	if !w.opts.DropOldest {
+		w.events <- e
+		return
+	}
+	select {
+	case w.events <- e:
+		return
+	default:
+	}
+	select {
+	case <-w.events:
+	default:
+	}
+	select {
+	case w.events <- e:
+	default:
+	}
+}
+
+// Flush blocks until events queued so far have been sent, or timeout
+// elapses. It reports whether the flush completed in time, and returns
+// false immediately once the worker has started shutting down.
+func (w *sentryWorker) Flush(timeout time.Duration) bool {
+	w.closeMu.RLock()
+	if w.closed {
+		w.closeMu.RUnlock()
+		return false
+	}
+	done := make(chan struct{})
+	select {
+	case w.events <- sentryEvent{flushed: done}:
+		w.closeMu.RUnlock()
+	case <-time.After(timeout):
+		w.closeMu.RUnlock()
+		return false
+	}
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// Wait blocks until the worker has shut down and drained its queue, or
+// until ctx is done.
+func (w *sentryWorker) Wait(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// shutdown closes the event channel so run's receive loop drains whatever
+// is still queued, sends a final flush, and exits, then waits for it to do
+// so before returning. It is idempotent: a second call is a no-op.
+func (w *sentryWorker) shutdown() {
+	w.closeMu.Lock()
+	if w.closed {
+		w.closeMu.Unlock()
+		return
+	}
+	w.closed = true
+	close(w.events)
+	w.closeMu.Unlock()
+
+	w.wg.Wait()
+}
+
+// Flush blocks until events queued with the connected Sentry worker have
+// been sent, or timeout elapses. It returns true if nothing is connected.
+func Flush(timeout time.Duration) bool {
+	w := currentSentryWorker()
+	if w == nil {
+		return true
+	}
+	return w.Flush(timeout)
+}
+
+// Wait blocks until the connected Sentry worker has shut down and drained
+// its queue, or until ctx is done. It returns nil immediately if nothing is
+// connected.
+func Wait(ctx context.Context) error {
+	w := currentSentryWorker()
+	if w == nil {
+		return nil
+	}
+	return w.Wait(ctx)
+}
+
+// CloseSentry shuts down the Sentry worker started by ConnectSentry,
+// draining any events still queued first. All Loggers share the one
+// connected worker, so this is the place to shut it down rather than
+// *Logger.Close, which every Logger instance calls. It is idempotent and a
+// no-op if no worker is connected.
+func CloseSentry() {
+	sentryMu.Lock()
+	w := sentryW
+	sentryW = nil
+	sentryMu.Unlock()
+
+	if w != nil {
+		w.shutdown()
+	}
+}
+
+var (
+	sentryFieldsMu sync.Mutex
+	sentryFields   Fields
+)
+
+// withSentryFields makes fields available to snio.Write for the duration of
+// fn, so an Entry's fields are forwarded to Sentry as Extras/Tags alongside
+// the rendered message rather than only as flat text.
+func withSentryFields(fields Fields, fn func()) {
+	sentryFieldsMu.Lock()
+	sentryFields = fields
+	sentryFieldsMu.Unlock()
+
+	fn()
+
+	sentryFieldsMu.Lock()
+	sentryFields = nil
+	sentryFieldsMu.Unlock()
+}
+
+var (
+	sentryStacktraceMu sync.Mutex
+	sentryStacktrace   *sentry.Stacktrace
+)
+
+// setSentryStacktrace and clearSentryStacktrace let Logger.emit attach a
+// parsed stack (captured at a -log_backtrace_at trigger) to the next event
+// written via snio.Write, without threading it through every log call.
+func setSentryStacktrace(st *sentry.Stacktrace) {
+	sentryStacktraceMu.Lock()
+	sentryStacktrace = st
+	sentryStacktraceMu.Unlock()
+}
+
+func clearSentryStacktrace() {
+	sentryStacktraceMu.Lock()
+	sentryStacktrace = nil
+	sentryStacktraceMu.Unlock()
+}
+
+type snio struct {
+	level sentry.Level
+}
+
+func (sn *snio) Write(p []byte) (n int, err error) {
+	w := currentSentryWorker()
+	if w == nil {
+		return 0, nil
+	}
+
+	sentryFieldsMu.Lock()
+	fields := sentryFields
+	sentryFieldsMu.Unlock()
+
+	sentryStacktraceMu.Lock()
+	stacktrace := sentryStacktrace
+	sentryStacktraceMu.Unlock()
+
+	w.push(sentryEvent{level: sn.level, message: string(p), fields: fields, time: time.Now(), stacktrace: stacktrace})
+
+	if sn.level == sentry.LevelFatal {
+		// Fatal must synchronously drain before os.Exit.
+		w.Flush(flashTimeout)
+	}
+	return 0, nil
+}
@@ -0,0 +1,27 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestLoggerCloseDoesNotTearDownSharedSentryWorker is a regression test for
+// Logger.Close shutting down the package-level Sentry worker that every
+// Logger shares, which crashed any other still-open Logger's next
+// Sentry-routed log call with "send on closed channel".
+func TestLoggerCloseDoesNotTearDownSharedSentryWorker(t *testing.T) {
+	if err := ConnectSentry("", SentryOptions{}); err != nil {
+		t.Fatalf("ConnectSentry: %v", err)
+	}
+	defer CloseSentry()
+
+	var bufA, bufB bytes.Buffer
+	a := Init("sentry_test_a", false, false, &bufA)
+	b := Init("sentry_test_b", false, false, &bufB)
+
+	a.Close()
+
+	// Must not panic: b still shares the Sentry worker a.Close tore down
+	// before this fix.
+	b.Warning("still alive")
+}
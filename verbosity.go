@@ -0,0 +1,243 @@
+package logger
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Level specifies a level of verbosity for V logs. *Level implements
+// flag.Value; the -v flag is of type Level and should be modified only
+// through the flag.Value interface.
+type Level int32
+
+// get returns the value of the Level.
+func (l *Level) get() Level {
+	return Level(atomic.LoadInt32((*int32)(l)))
+}
+
+// set sets the value of the Level.
+func (l *Level) set(val Level) {
+	atomic.StoreInt32((*int32)(l), int32(val))
+}
+
+// String is part of the flag.Value interface.
+func (l *Level) String() string {
+	return strconv.FormatInt(int64(*l), 10)
+}
+
+// Set is part of the flag.Value interface.
+func (l *Level) Set(value string) error {
+	v, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return err
+	}
+	l.set(Level(v))
+	return nil
+}
+
+// moduleSpec represents the settings for the -vmodule flag.
+type moduleSpec struct {
+	filter []modulePat
+}
+
+// modulePat contains a filter for the -vmodule flag. It holds a verbosity
+// level and a file pattern to match.
+type modulePat struct {
+	pattern string
+	literal bool // The pattern is a literal string rather than a glob pattern.
+	level   Level
+}
+
+// match reports whether the file matches the pattern, either as a glob
+// (with * and ? wildcards) or, if the pattern is literal, as an exact match.
+func (m *modulePat) match(file string) bool {
+	if m.literal {
+		return file == m.pattern
+	}
+	match, _ := filepath.Match(m.pattern, file)
+	return match
+}
+
+func (m *moduleSpec) String() string {
+	var b strings.Builder
+	for i, f := range m.filter {
+		if i > 0 {
+			b.WriteRune(',')
+		}
+		fmt.Fprintf(&b, "%s=%d", f.pattern, f.level)
+	}
+	return b.String()
+}
+
+// Set is part of the flag.Value interface and parses a comma-separated list
+// of pattern=N settings, e.g. "gopher*=1,foo.go=3".
+func (m *moduleSpec) Set(value string) error {
+	var filter []modulePat
+	for _, pat := range strings.Split(value, ",") {
+		if pat == "" {
+			continue
+		}
+		patLev := strings.Split(pat, "=")
+		if len(patLev) != 2 || len(patLev[0]) == 0 || len(patLev[1]) == 0 {
+			return fmt.Errorf("syntax error: expect comma-separated list of filename=N")
+		}
+		pattern := patLev[0]
+		v, err := strconv.ParseInt(patLev[1], 10, 32)
+		if err != nil {
+			return fmt.Errorf("syntax error: expect comma-separated list of filename=N")
+		}
+		literal := true
+		if strings.ContainsAny(pattern, `*?`) {
+			literal = false
+		}
+		filter = append(filter, modulePat{pattern, literal, Level(v)})
+	}
+	vmoduleMu.Lock()
+	m.filter = filter
+	vmoduleMu.Unlock()
+
+	// Swap in a fresh cache rather than mutating the shared sync.Map, so a
+	// VDepth call racing this reset can never observe a sync.Map value
+	// being reset out from under its own in-flight Load/Store.
+	vmodulePCCache.Store(&sync.Map{})
+	return nil
+}
+
+var (
+	verbosity Level
+
+	vmoduleMu sync.Mutex
+	vmodule   moduleSpec
+
+	// vmodulePCCache holds a *sync.Map (map[uintptr]Level, keyed by the
+	// caller's PC), swapped atomically on every -vmodule reset.
+	vmodulePCCache atomic.Value
+)
+
+func init() {
+	flag.Var(&verbosity, "v", "log level for V logs")
+	flag.Var(&vmodule, "vmodule", "comma-separated list of pattern=N settings for file-filtered logging")
+	vmodulePCCache.Store(&sync.Map{})
+}
+
+// Config carries the settings that would otherwise be supplied via flags,
+// for programs that want to configure verbosity without parsing flag.CommandLine.
+type Config struct {
+	Verbose bool
+	V       Level
+	VModule string
+}
+
+// InitWithConfig behaves like Init but also applies verbosity settings from
+// cfg, without requiring -v/-vmodule to be parsed from the command line.
+func InitWithConfig(name string, cfg Config, logFile io.Writer) *Logger {
+	l := Init(name, cfg.Verbose, false, logFile)
+	verbosity.set(cfg.V)
+	if cfg.VModule != "" {
+		vmodule.Set(cfg.VModule)
+	}
+	return l
+}
+
+// Verbose is a boolean type that implements Info, Infoln and Infof. These
+// methods write only if the level passed to V is less than or equal to the
+// value of the -v flag, or to the level of a -vmodule pattern matching the
+// caller's source file.
+// The value of Verbose is obtained by calling V(level).
+type Verbose bool
+
+// V reports whether verbosity at the given level is enabled for the caller's
+// source file. The returned Verbose value is then used to guard expensive
+// log statements: `if logger.V(2) { logger.Info(...) }`.
+func V(level Level) Verbose {
+	return VDepth(1, level)
+}
+
+// VDepth behaves like V but uses depth to determine the caller whose source
+// file is checked against -vmodule, allowing wrapper functions to report
+// the verbosity of their own caller rather than themselves.
+func VDepth(depth int, level Level) Verbose {
+	if verbosity.get() >= level {
+		return Verbose(true)
+	}
+
+	pc, _, _, ok := runtime.Caller(depth + 1)
+	if !ok {
+		return Verbose(false)
+	}
+
+	vmoduleMu.Lock()
+	filter := vmodule.filter
+	vmoduleMu.Unlock()
+	if len(filter) == 0 {
+		return Verbose(false)
+	}
+
+	cache := vmodulePCCache.Load().(*sync.Map)
+	if v, ok := cache.Load(pc); ok {
+		return Verbose(v.(Level) >= level)
+	}
+
+	v := vmoduleLevel(pc, filter)
+	cache.Store(pc, v)
+	return Verbose(v >= level)
+}
+
+// vmoduleLevel resolves the -vmodule level configured for the source file
+// that contains pc, checking both the bare filename and the full path
+// against each pattern.
+func vmoduleLevel(pc uintptr, filter []modulePat) Level {
+	frames := runtime.CallersFrames([]uintptr{pc})
+	frame, _ := frames.Next()
+	file := frame.File
+	base := filepath.Base(file)
+
+	var level Level = -1
+	for _, f := range filter {
+		if f.match(base) || f.match(file) {
+			if f.level > level {
+				level = f.level
+			}
+		}
+	}
+	return level
+}
+
+// Info is equivalent to the global Info function, guarded by the value of v.
+// See the documentation of V for usage.
+func (v Verbose) Info(args ...interface{}) {
+	if v {
+		defaultLogger.output(sInfo, 0, fmt.Sprint(args...))
+	}
+}
+
+// Infoln is equivalent to the global Infoln function, guarded by the value of v.
+// See the documentation of V for usage.
+func (v Verbose) Infoln(args ...interface{}) {
+	if v {
+		defaultLogger.output(sInfo, 0, fmt.Sprintln(args...))
+	}
+}
+
+// Infof is equivalent to the global Infof function, guarded by the value of v.
+// See the documentation of V for usage.
+func (v Verbose) Infof(format string, args ...interface{}) {
+	if v {
+		defaultLogger.output(sInfo, 0, fmt.Sprintf(format, args...))
+	}
+}
+
+// InfoDepth is equivalent to the global InfoDepth function, guarded by the
+// value of v. See the documentation of V for usage.
+func (v Verbose) InfoDepth(depth int, args ...interface{}) {
+	if v {
+		defaultLogger.output(sInfo, depth, fmt.Sprint(args...))
+	}
+}
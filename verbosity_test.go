@@ -0,0 +1,32 @@
+package logger
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestVDepthRaceWithVModuleReset is a regression test for vmodulePCCache
+// being reassigned under vmoduleMu while VDepth accessed it without holding
+// any lock, which crashed go test -race with a concurrent V() call racing a
+// -vmodule reset.
+func TestVDepthRaceWithVModuleReset(t *testing.T) {
+	defer vmodule.Set("")
+
+	if err := vmodule.Set("verbosity_test.go=2"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			VDepth(1, 1)
+		}()
+		go func() {
+			defer wg.Done()
+			vmodule.Set("verbosity_test.go=2")
+		}()
+	}
+	wg.Wait()
+}